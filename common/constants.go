@@ -0,0 +1,23 @@
+package common
+
+// RebootNeededAnnotation is set by node-level tooling (e.g. update-engine, a
+// package manager post-install hook) to signal that a reboot is required to
+// apply a pending change.
+//
+// This predates the reboot.k8s.example.com RebootRequest API: reboot-controller
+// still honors it as a legacy trigger, shimming a RebootRequest into existence
+// on behalf of any node carrying it, but new tooling should create a
+// RebootRequest directly instead.
+const RebootNeededAnnotation = "reboot.k8s.example.com/reboot-needed"
+
+// NodeGroupLabel groups nodes for the purpose of per-group maxUnavailable
+// accounting (e.g. "master" vs "worker"), so that reboots in one group can't
+// starve the other of available capacity. Nodes without this label are all
+// treated as members of a single group named "".
+const NodeGroupLabel = "reboot.k8s.example.com/group"
+
+// RebootStartedAtAnnotation records, on the Node, the RFC3339 timestamp at
+// which reboot-agent issued a reboot. reboot-agent's own process doesn't
+// survive the reboot it measures, so this is how it recovers the start time
+// on the other side to report reboot_agent_reboot_duration_seconds.
+const RebootStartedAtAnnotation = "reboot.k8s.example.com/reboot-started-at"
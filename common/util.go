@@ -1,7 +1,6 @@
 package common
 
 import (
-	"k8s.io/api/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
@@ -12,11 +11,3 @@ func GetClientConfig(kubeconfig string) (*rest.Config, error) {
 	}
 	return rest.InClusterConfig()
 }
-
-func CopyObjToNode(obj interface{}) (*v1.Node, error) {
-	node := obj.(*v1.Node).DeepCopy()
-	if node.Annotations == nil {
-		node.Annotations = make(map[string]string)
-	}
-	return node, nil
-}
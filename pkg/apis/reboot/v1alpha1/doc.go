@@ -0,0 +1,7 @@
+// +k8s:deepcopy-gen=package
+
+// Package v1alpha1 is the v1alpha1 version of the reboot.k8s.example.com API
+// group. It defines the RebootRequest type used to coordinate node reboots
+// between reboot-controller and reboot-agent.
+// +groupName=reboot.k8s.example.com
+package v1alpha1
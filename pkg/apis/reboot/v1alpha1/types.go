@@ -0,0 +1,105 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RebootRequestPhase is the current phase of a RebootRequest.
+type RebootRequestPhase string
+
+const (
+	RebootRequestPending   RebootRequestPhase = "Pending"
+	RebootRequestApproved  RebootRequestPhase = "Approved"
+	RebootRequestDraining  RebootRequestPhase = "Draining"
+	RebootRequestRebooting RebootRequestPhase = "Rebooting"
+	RebootRequestCompleted RebootRequestPhase = "Completed"
+	RebootRequestFailed    RebootRequestPhase = "Failed"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RebootRequest represents a single request to reboot a node. It is
+// cluster-scoped, like Node, which it targets via spec.nodeName. It replaces the
+// annotation-only coordination previously carried on the Node object, giving
+// reboot-controller and reboot-agent a place to record why a reboot was
+// requested and to track its progress through approval, draining, and
+// completion.
+type RebootRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RebootRequestSpec   `json:"spec"`
+	Status RebootRequestStatus `json:"status,omitempty"`
+}
+
+// RebootRequestSpec is the desired state of a RebootRequest.
+type RebootRequestSpec struct {
+	// NodeName is the node this request applies to.
+	NodeName string `json:"nodeName"`
+
+	// Reason is a human-readable description of why the reboot is needed,
+	// e.g. "kernel update pending".
+	Reason string `json:"reason,omitempty"`
+
+	// DrainPolicy controls how the reboot-agent drains the node before
+	// rebooting it.
+	// +optional
+	DrainPolicy DrainPolicy `json:"drainPolicy,omitempty"`
+
+	// MinAvailable overrides the controller's cluster-wide --min-available
+	// flag for this specific request, if set.
+	// +optional
+	MinAvailable *int32 `json:"minAvailable,omitempty"`
+}
+
+// DrainPolicy controls how a node is drained prior to reboot.
+type DrainPolicy struct {
+	// IgnoreDaemonSets, if true, does not wait for DaemonSet-managed pods to
+	// be evicted before rebooting.
+	IgnoreDaemonSets bool `json:"ignoreDaemonSets,omitempty"`
+
+	// Force, if true, proceeds with the reboot even if some pods could not
+	// be evicted.
+	Force bool `json:"force,omitempty"`
+
+	// TimeoutSeconds bounds how long to wait for the drain to complete.
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty"`
+}
+
+// RebootRequestStatus is the observed state of a RebootRequest.
+type RebootRequestStatus struct {
+	Phase RebootRequestPhase `json:"phase,omitempty"`
+
+	// Conditions holds additional detail about the current phase, e.g. why a
+	// request is stuck Pending or why it Failed.
+	// +optional
+	Conditions []RebootRequestCondition `json:"conditions,omitempty"`
+
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// RebootRequestCondition describes a point-in-time observation about a
+// RebootRequest, following the same convention as corev1.NodeCondition.
+type RebootRequestCondition struct {
+	Type               string                 `json:"type"`
+	Status             corev1.ConditionStatus `json:"status"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+	LastTransitionTime metav1.Time            `json:"lastTransitionTime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RebootRequestList is a list of RebootRequests.
+type RebootRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []RebootRequest `json:"items"`
+}
@@ -0,0 +1,156 @@
+// Package controller provides the shared scaffolding used by the
+// reboot-controller and reboot-agent binaries (and any future controllers
+// added to this repo): a single SharedInformerFactory so controllers don't
+// each open their own watch against the apiserver, and a thin wrapper around
+// leader election so a controller can be run with multiple replicas for HA.
+package controller
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+
+	rebootclientset "github.com/aaronlevy/kube-controller-demo/pkg/client/clientset/versioned"
+	rebootinformers "github.com/aaronlevy/kube-controller-demo/pkg/client/informers/externalversions"
+	rebootlisters "github.com/aaronlevy/kube-controller-demo/pkg/client/listers/reboot/v1alpha1"
+)
+
+// Runnable is implemented by controllers that are registered with a Manager.
+// Start is called once the manager's informer caches have synced, and should
+// block - processing work off of its queue - until stopCh is closed.
+type Runnable interface {
+	Start(ctx context.Context, stopCh <-chan struct{}) error
+}
+
+// Manager owns the SharedInformerFactories that registered controllers watch
+// against, so that every controller registered with it in a process shares a
+// single watch on Nodes (and on RebootRequests) rather than each establishing
+// their own. reboot-controller and reboot-agent are separate binaries, and
+// each builds its own Manager.
+type Manager struct {
+	factory       informers.SharedInformerFactory
+	rebootFactory *rebootinformers.SharedInformerFactory
+
+	NodeLister          corelisters.NodeLister
+	RebootRequestLister rebootlisters.RebootRequestLister
+
+	runnables []Runnable
+}
+
+// NewManager builds a Manager backed by a SharedInformerFactory for the core
+// API group and one for the reboot.k8s.example.com group, both using the
+// given resync period.
+func NewManager(client kubernetes.Interface, rebootClient rebootclientset.Interface, resync time.Duration) *Manager {
+	factory := informers.NewSharedInformerFactory(client, resync)
+	rebootFactory := rebootinformers.NewSharedInformerFactory(rebootClient, resync)
+	return &Manager{
+		factory:             factory,
+		rebootFactory:       rebootFactory,
+		NodeLister:          factory.Core().V1().Nodes().Lister(),
+		RebootRequestLister: rebootFactory.Reboot().V1alpha1().RebootRequests().Lister(),
+	}
+}
+
+// NodeInformer exposes the shared Node informer so controllers can register
+// their own event handlers against it.
+func (m *Manager) NodeInformer() cache.SharedIndexInformer {
+	return m.factory.Core().V1().Nodes().Informer()
+}
+
+// RebootRequestInformer exposes the shared RebootRequest informer so
+// controllers can register their own event handlers against it.
+func (m *Manager) RebootRequestInformer() cache.SharedIndexInformer {
+	return m.rebootFactory.Reboot().V1alpha1().RebootRequests().Informer()
+}
+
+// Register adds a controller to be started once the informer caches have
+// synced. Register must be called before Run.
+func (m *Manager) Register(r Runnable) {
+	m.runnables = append(m.runnables, r)
+}
+
+// Run starts both informer factories, waits for every informer's cache to
+// sync, then starts each registered Runnable. It blocks until stopCh is
+// closed.
+func (m *Manager) Run(ctx context.Context, stopCh <-chan struct{}) {
+	logger := klog.FromContext(ctx)
+
+	m.factory.Start(stopCh)
+	m.rebootFactory.Start(stopCh)
+
+	logger.Info("Waiting for informer caches to sync")
+	for informerType, synced := range m.factory.WaitForCacheSync(stopCh) {
+		if !synced {
+			logger.Error(nil, "Failed to sync informer cache", "informer", informerType)
+			os.Exit(1)
+		}
+	}
+	for informerType, synced := range m.rebootFactory.WaitForCacheSync(stopCh) {
+		if !synced {
+			logger.Error(nil, "Failed to sync informer cache", "informer", informerType)
+			os.Exit(1)
+		}
+	}
+
+	for _, r := range m.runnables {
+		go func(r Runnable) {
+			if err := r.Start(ctx, stopCh); err != nil {
+				logger.Error(err, "Controller exited")
+			}
+		}(r)
+	}
+
+	<-stopCh
+}
+
+// LeaderElectionConfig holds the knobs exposed as flags by binaries that want
+// to run with multiple replicas for HA.
+type LeaderElectionConfig struct {
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+// RunOrDie blocks running `run` only while this process holds the named
+// ConfigMap lock, so that at most one replica of a leader-elected controller
+// is active at a time. It never returns.
+func RunOrDie(ctx context.Context, client kubernetes.Interface, namespace, name, identity string, cfg LeaderElectionConfig, run func(stopCh <-chan struct{})) {
+	logger := klog.FromContext(ctx)
+
+	lock, err := resourcelock.New(
+		resourcelock.ConfigMapsResourceLock,
+		namespace,
+		name,
+		client.CoreV1(),
+		client.CoordinationV1(),
+		resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	)
+	if err != nil {
+		logger.Error(err, "Failed to create leader election lock")
+		os.Exit(1)
+	}
+
+	leaderelection.RunOrDie(leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: cfg.LeaseDuration,
+		RenewDeadline: cfg.RenewDeadline,
+		RetryPeriod:   cfg.RetryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) { run(ctx.Done()) },
+			OnStoppedLeading: func() {
+				logger.Error(nil, "Lost leader lease, exiting", "namespace", namespace, "name", name)
+				os.Exit(1)
+			},
+		},
+	})
+}
@@ -0,0 +1,50 @@
+// Package metrics registers the Prometheus collectors reboot-controller and
+// reboot-agent use to report on reboot activity, and serves them over HTTP.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// NodesUnavailable tracks, per NodeGroup, how many nodes are currently
+	// unavailable (rebooting, or NotReady).
+	NodesUnavailable = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "reboot_controller_nodes_unavailable",
+		Help: "Number of nodes currently unavailable (rebooting, or NotReady), by NodeGroup.",
+	}, []string{"group"})
+
+	// RebootsApprovedTotal counts every RebootRequest the controller approves.
+	RebootsApprovedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "reboot_controller_reboots_approved_total",
+		Help: "Total number of RebootRequests approved.",
+	})
+
+	// RebootsDeniedTotal counts RebootRequests the controller declined to
+	// approve on a given sync, by reason (e.g. "max-unavailable",
+	// "window-closed", "node-selector"). A request denied for one reason on
+	// one sync may still be approved later once the reason no longer applies.
+	RebootsDeniedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "reboot_controller_reboots_denied_total",
+		Help: "Total number of times a RebootRequest was not approved on a sync, by reason.",
+	}, []string{"reason"})
+
+	// RebootDurationSeconds measures the time from reboot-agent issuing a
+	// reboot (after draining) to the RebootRequest's completion once the agent
+	// is back up. Because the agent issuing the reboot doesn't survive
+	// it, this is measured using a timestamp persisted on the Node rather
+	// than an in-memory timer.
+	RebootDurationSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "reboot_agent_reboot_duration_seconds",
+		Help:    "Time from the reboot being issued to completion, measured across the agent's own restart.",
+		Buckets: prometheus.ExponentialBuckets(10, 2, 10),
+	})
+
+	// DrainFailuresTotal counts how many times draining a node failed prior
+	// to a reboot being issued.
+	DrainFailuresTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "reboot_agent_drain_failures_total",
+		Help: "Total number of times draining a node failed prior to reboot.",
+	})
+)
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// evictionBackoff bounds the retries issued against a pod's Eviction
+// subresource while its PodDisruptionBudget is blocking eviction.
+var evictionBackoff = wait.Backoff{Duration: time.Second, Factor: 2, Steps: 6}
+
+// drainNode cordons the node so no new pods are scheduled to it, then evicts
+// the pods on it that podsToEvict selects (honoring PodDisruptionBudgets via the Eviction
+// subresource) and waits for them to disappear. cfg is the effective
+// drainConfig for this reboot - the agent's flag-derived defaults, as
+// overridden by the RebootRequest's DrainPolicy.
+func (a *rebootAgent) drainNode(cfg drainConfig) error {
+	if err := a.cordon(); err != nil {
+		return fmt.Errorf("failed to cordon node: %v", err)
+	}
+
+	pods, err := a.podsToEvict(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to list pods on node: %v", err)
+	}
+
+	var toWaitFor []corev1.Pod
+	for _, pod := range pods {
+		if err := a.evictPod(pod); err != nil {
+			if cfg.force {
+				a.logger.Error(err, "Failed to evict pod, continuing because --force", "pod", pod.Namespace+"/"+pod.Name)
+				continue // Not evicted - don't wait for it to disappear either
+			}
+			return fmt.Errorf("failed to evict pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		}
+		toWaitFor = append(toWaitFor, pod)
+	}
+
+	return a.waitForDrain(toWaitFor, cfg.timeout)
+}
+
+// cordon marks the node unschedulable so the scheduler stops placing new
+// pods on it while it drains and reboots.
+func (a *rebootAgent) cordon() error {
+	node, err := a.client.CoreV1().Nodes().Get(a.ctx, a.nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if node.Spec.Unschedulable {
+		return nil
+	}
+
+	nodeCopy := node.DeepCopy()
+	nodeCopy.Spec.Unschedulable = true
+	_, err = a.client.CoreV1().Nodes().Update(a.ctx, nodeCopy, metav1.UpdateOptions{})
+	return err
+}
+
+// uncordon reverses cordon, marking the node schedulable again. It is called
+// once the reboot cycle that justified cordoning the node is over, whether it
+// succeeded or failed, so a node doesn't stay stuck out of the scheduling
+// pool forever.
+func (a *rebootAgent) uncordon() error {
+	node, err := a.client.CoreV1().Nodes().Get(a.ctx, a.nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !node.Spec.Unschedulable {
+		return nil
+	}
+
+	nodeCopy := node.DeepCopy()
+	nodeCopy.Spec.Unschedulable = false
+	_, err = a.client.CoreV1().Nodes().Update(a.ctx, nodeCopy, metav1.UpdateOptions{})
+	return err
+}
+
+// podsToEvict lists the pods running on this node that reboot-agent is
+// responsible for evicting. Mirror (static) pods are always excluded, since
+// they cannot be rescheduled elsewhere by an eviction; DaemonSet-managed pods
+// are excluded only when cfg.ignoreDaemonSets is set, and are evicted otherwise.
+func (a *rebootAgent) podsToEvict(cfg drainConfig) ([]corev1.Pod, error) {
+	fieldSelector := fields.OneTermEqualSelector("spec.nodeName", a.nodeName).String()
+	podList, err := a.client.CoreV1().Pods(metav1.NamespaceAll).List(a.ctx, metav1.ListOptions{FieldSelector: fieldSelector})
+	if err != nil {
+		return nil, err
+	}
+
+	var pods []corev1.Pod
+	for _, pod := range podList.Items {
+		if isMirrorPod(&pod) {
+			continue
+		}
+		if cfg.ignoreDaemonSets && isDaemonSetPod(&pod) {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+func isMirrorPod(pod *corev1.Pod) bool {
+	_, ok := pod.Annotations[corev1.MirrorPodAnnotationKey]
+	return ok
+}
+
+func isDaemonSetPod(pod *corev1.Pod) bool {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return false
+}
+
+// evictPod requests eviction of a single pod through the Eviction
+// subresource, so that PodDisruptionBudgets are respected. It retries with
+// backoff while the apiserver responds 429 TooManyRequests (the PDB is
+// temporarily blocking eviction).
+func (a *rebootAgent) evictPod(pod corev1.Pod) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+	}
+
+	return wait.ExponentialBackoff(evictionBackoff, func() (bool, error) {
+		err := a.client.PolicyV1().Evictions(pod.Namespace).Evict(a.ctx, eviction)
+		switch {
+		case err == nil, apierrors.IsNotFound(err):
+			return true, nil
+		case apierrors.IsTooManyRequests(err):
+			a.logger.V(4).Info("PodDisruptionBudget blocking eviction, retrying", "pod", pod.Namespace+"/"+pod.Name)
+			return false, nil
+		default:
+			return false, err
+		}
+	})
+}
+
+// waitForDrain blocks until every pod in pods has disappeared from the
+// apiserver, or timeout elapses. pods should only contain pods that were
+// actually evicted - under --force, pods that could not be evicted are left
+// running and must not be waited on here.
+func (a *rebootAgent) waitForDrain(pods []corev1.Pod, timeout time.Duration) error {
+	if len(pods) == 0 {
+		return nil
+	}
+
+	return wait.PollImmediate(time.Second, timeout, func() (bool, error) {
+		for _, pod := range pods {
+			_, err := a.client.CoreV1().Pods(pod.Namespace).Get(a.ctx, pod.Name, metav1.GetOptions{})
+			if err == nil {
+				return false, nil // Still present - keep waiting
+			}
+			if !apierrors.IsNotFound(err) {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
@@ -1,22 +1,28 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
 	"time"
 
 	"github.com/coreos/go-systemd/login1"
-	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/fields"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
-	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/pkg/api/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
 
+	rebootv1alpha1 "github.com/aaronlevy/kube-controller-demo/pkg/apis/reboot/v1alpha1"
+	rebootclientset "github.com/aaronlevy/kube-controller-demo/pkg/client/clientset/versioned"
+	rebootlisters "github.com/aaronlevy/kube-controller-demo/pkg/client/listers/reboot/v1alpha1"
 	"github.com/aaronlevy/kube-controller-demo/common"
+	"github.com/aaronlevy/kube-controller-demo/pkg/controller"
+	"github.com/aaronlevy/kube-controller-demo/pkg/metrics"
 )
 
 const nodeNameEnv = "NODE_NAME"
@@ -25,154 +31,299 @@ func main() {
 	// When running as a pod in-cluster, a kubeconfig is not needed. Instead this will make use of the service account injected into the pod.
 	// However, allow the use of a local kubeconfig as this can make local development & testing easier.
 	kubeconfig := flag.String("kubeconfig", "", "Path to a kubeconfig file")
+	force := flag.Bool("force", false, "Continue draining even if some pods cannot be evicted")
+	ignoreDaemonSets := flag.Bool("ignore-daemonsets", true, "Don't wait on DaemonSet-managed pods when draining")
+	drainTimeout := flag.Duration("drain-timeout", 5*time.Minute, "How long to wait for the node to finish draining before giving up")
+	metricsAddr := flag.String("metrics-addr", ":8080", "Address to serve /metrics and /healthz on")
 
-	// We log to stderr because glog will default to logging to a file.
-	// By setting this debugging is easier via `kubectl logs`
-	flag.Set("logtostderr", "true")
+	klog.InitFlags(nil)
 	flag.Parse()
 
+	ctx := klog.NewContext(context.Background(), klog.NewKlogr().WithValues("controller", "reboot-agent"))
+	logger := klog.FromContext(ctx)
+
+	go func() {
+		if err := metrics.Serve(*metricsAddr); err != nil {
+			logger.Error(err, "Metrics server exited")
+			os.Exit(1)
+		}
+	}()
+
 	// The node name is necessary so we can identify "self".
 	// This environment variable is assumed to be set via the pod downward-api, however it can be manually set during testing
 	nodeName := os.Getenv(nodeNameEnv)
 	if nodeName == "" {
-		glog.Fatalf("Missing required environment variable %s", nodeNameEnv)
+		logger.Error(nil, "Missing required environment variable", "variable", nodeNameEnv)
+		os.Exit(1)
 	}
+	logger = logger.WithValues("node", nodeName)
+	ctx = klog.NewContext(ctx, logger)
 
 	// Build the client config - optionally using a provided kubeconfig file.
 	config, err := common.GetClientConfig(*kubeconfig)
 	if err != nil {
-		glog.Fatalf("Failed to load client config: %v", err)
+		logger.Error(err, "Failed to load client config")
+		os.Exit(1)
 	}
 
 	// Construct the Kubernetes client
 	client, err := kubernetes.NewForConfig(config)
 	if err != nil {
-		glog.Fatalf("Failed to create kubernetes client: %v", err)
+		logger.Error(err, "Failed to create kubernetes client")
+		os.Exit(1)
+	}
+
+	// Construct the client for the reboot.k8s.example.com/v1alpha1 API group
+	rebootClient, err := rebootclientset.NewForConfig(config)
+	if err != nil {
+		logger.Error(err, "Failed to create reboot-request client")
+		os.Exit(1)
 	}
 
 	// Open a dbus connection for triggering a system reboot
 	dbusConn, err := login1.New()
 	if err != nil {
-		glog.Fatalf("Failed to create dbus connection")
+		logger.Error(err, "Failed to create dbus connection")
+		os.Exit(1)
 	}
 
-	agent := newRebootAgent(nodeName, client, dbusConn)
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: client.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "reboot-agent"})
 
-	glog.Info("Starting Reboot Agent")
-	agent.controller.Run(wait.NeverStop)
+	mgr := controller.NewManager(client, rebootClient, 10*time.Second)
+	agent := newRebootAgent(ctx, nodeName, client, rebootClient, dbusConn, recorder, mgr, drainConfig{
+		force:            *force,
+		ignoreDaemonSets: *ignoreDaemonSets,
+		timeout:          *drainTimeout,
+	})
+	mgr.Register(agent)
+
+	logger.Info("Starting reboot agent")
+	mgr.Run(ctx, wait.NeverStop)
+}
+
+// drainConfig controls how a node is drained prior to reboot.
+type drainConfig struct {
+	force            bool
+	ignoreDaemonSets bool
+	timeout          time.Duration
+}
+
+// effectiveDrainConfig applies a RebootRequest's DrainPolicy on top of the
+// agent's flag-derived defaults. Force and IgnoreDaemonSets can only turn
+// their setting on - there's no way to ask for "force" or "ignore
+// DaemonSets" to be turned off for a single request. A positive
+// TimeoutSeconds replaces the default timeout, whether shorter or longer.
+func effectiveDrainConfig(base drainConfig, policy rebootv1alpha1.DrainPolicy) drainConfig {
+	cfg := base
+	if policy.Force {
+		cfg.force = true
+	}
+	if policy.IgnoreDaemonSets {
+		cfg.ignoreDaemonSets = true
+	}
+	if policy.TimeoutSeconds > 0 {
+		cfg.timeout = time.Duration(policy.TimeoutSeconds) * time.Second
+	}
+	return cfg
 }
 
 type rebootAgent struct {
-	client     kubernetes.Interface
-	dbusConn   *login1.Conn
-	controller cache.Controller
+	nodeName     string
+	client       kubernetes.Interface
+	rebootClient rebootclientset.Interface
+	dbusConn     *login1.Conn
+	recorder     record.EventRecorder
+	drainConfig  drainConfig
+
+	rebootRequestLister rebootlisters.RebootRequestLister
+	informer            cache.SharedIndexInformer
+
+	// ctx is threaded into any call made from an event handler, which
+	// (unlike a workqueue's processing loop) doesn't carry a context.Context
+	// of its own.
+	ctx    context.Context
+	logger klog.Logger
 }
 
-func newRebootAgent(nodeName string, client kubernetes.Interface, dbusConn *login1.Conn) *rebootAgent {
+func newRebootAgent(ctx context.Context, nodeName string, client kubernetes.Interface, rebootClient rebootclientset.Interface, dbusConn *login1.Conn, recorder record.EventRecorder, mgr *controller.Manager, drainConfig drainConfig) *rebootAgent {
 	agent := &rebootAgent{
-		client:   client,
-		dbusConn: dbusConn,
-	}
-
-	// We only care about updates to "self" so create a field selector based on the current node name
-	nodeNameFS := fields.OneTermEqualSelector("metadata.name", nodeName).String()
-
-	// We do not need the cache store of the informer. In this case we just want the controller event handlers.
-	_, controller := cache.NewInformer(
-		&cache.ListWatch{
-			ListFunc: func(lo metav1.ListOptions) (runtime.Object, error) {
-				// Add the field selector containgin our node name to our list options
-				lo.FieldSelector = nodeNameFS
-				return client.Core().Nodes().List(lo)
-			},
-			WatchFunc: func(lo metav1.ListOptions) (watch.Interface, error) {
-				// Add the field selector containgin our node name to our list options
-				lo.FieldSelector = nodeNameFS
-				return client.Core().Nodes().Watch(lo)
-			},
-		},
-		// The types of objects this informer will return
-		&v1.Node{},
-		// The resync period of this object. This will force a re-queue of all cached objects at this interval.
-		// Every object will trigger the `Updatefunc` even if there have been no actual updates triggered.
-		// In some cases you can set this to a very high interval - as you can assume you will see periodic updates in normal operation.
-		// The interval is set low here for demo purposes.
-		10*time.Second,
-		// Callback Functions to trigger on add/update/delete
-		cache.ResourceEventHandlerFuncs{
-			// AddFunc: func(obj interface{}) {}
-			UpdateFunc: agent.handleUpdate,
-			// DeleteFunc: func(obj interface{}) {}
-		},
-	)
-
-	agent.controller = controller
+		nodeName:            nodeName,
+		client:              client,
+		rebootClient:        rebootClient,
+		dbusConn:            dbusConn,
+		recorder:            recorder,
+		drainConfig:         drainConfig,
+		rebootRequestLister: mgr.RebootRequestLister,
+		informer:            mgr.RebootRequestInformer(),
+		ctx:                 ctx,
+		logger:              klog.FromContext(ctx),
+	}
+
+	// The shared informer watches all RebootRequests, so filter to requests
+	// targeting "self" in the handler rather than via a field selector on the
+	// watch.
+	agent.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    agent.handleRebootRequest,
+		UpdateFunc: func(old, new interface{}) { agent.handleRebootRequest(new) },
+	})
 
 	return agent
 }
 
-func (a *rebootAgent) handleUpdate(oldObj, newObj interface{}) {
-	// In an `UpdateFunc` handler, before doing any work, you might try and determine if there has ben an actual change between the oldObj and newObj.
-	// This could mean checking the `resourceVersion` of the objects, and if they are the same - there has been no change to the object.
-	// Or it might mean only inspecting fields that you care about (as seen below).
-	// However, you should be careful when ignoring updates to objects, as it is possible that prior update was missed,
-	// and if you continue to ignore the objects, you will never fully sync desired state.
-
-	// Because we are about to make changes to the object - we make a copy.
-	// You should never mutate the original objects (from the cache.Store) as you are modifying state that has not been persisted via the apiserver.
-	// For example, if you modify the original object, but then your `Update()` call fails - your local cache could now be "wrong".
-	// Additionally, if using SharedInformers - you are modifying a local cache that could be used by other controllers.
-	node, err := common.CopyObjToNode(newObj)
+// Start blocks until stopCh is closed. All of the agent's work happens in its
+// event handler, which the manager's shared informer drives once its cache
+// has synced.
+func (a *rebootAgent) Start(ctx context.Context, stopCh <-chan struct{}) error {
+	<-stopCh
+	return nil
+}
+
+func (a *rebootAgent) handleRebootRequest(obj interface{}) {
+	rr, ok := obj.(*rebootv1alpha1.RebootRequest)
+	if !ok || rr.Spec.NodeName != a.nodeName {
+		return // Only requests targeting "self" matter to this agent
+	}
+
+	a.logger.V(4).Info("Received update for RebootRequest", "rebootRequest", rr.Name, "phase", rr.Status.Phase)
+
+	switch rr.Status.Phase {
+	case rebootv1alpha1.RebootRequestApproved:
+		a.reboot(rr)
+	case rebootv1alpha1.RebootRequestRebooting:
+		// We set Rebooting immediately before issuing the reboot call, and
+		// the call to Reboot() below blocks until the machine restarts. If
+		// we observe a request still in this phase, it's because our prior
+		// process (before this restart) reached here, issued the reboot, and
+		// never got to mark it Completed.
+		a.completeReboot(rr)
+	}
+}
+
+// reboot drains and reboots the node for an Approved RebootRequest.
+func (a *rebootAgent) reboot(rr *rebootv1alpha1.RebootRequest) {
+	name := rr.Name
+	logger := a.logger.WithValues("rebootRequest", name)
+	logger.Info("Reboot approved")
+
+	rr, err := a.setPhase(rr, rebootv1alpha1.RebootRequestDraining)
 	if err != nil {
-		glog.Errorf("Failed to copy Node object: %v", err)
+		logger.Error(err, "Failed to set RebootRequest phase", "phase", rebootv1alpha1.RebootRequestDraining)
+		return
+	}
+
+	if err := a.drainNode(effectiveDrainConfig(a.drainConfig, rr.Spec.DrainPolicy)); err != nil {
+		logger.Error(err, "Failed to drain node, reboot stalled")
+		metrics.DrainFailuresTotal.Inc()
+		a.recordDrainFailure(err)
+		if err := a.uncordon(); err != nil {
+			logger.Error(err, "Failed to uncordon node after failed drain")
+		}
+		if _, err := a.setPhase(rr, rebootv1alpha1.RebootRequestFailed); err != nil {
+			logger.Error(err, "Failed to set RebootRequest phase", "phase", rebootv1alpha1.RebootRequestFailed)
+		}
 		return
 	}
 
-	glog.V(4).Infof("Received update for node: %s", node.Name)
+	if _, err := a.setPhase(rr, rebootv1alpha1.RebootRequestRebooting); err != nil {
+		logger.Error(err, "Failed to set RebootRequest phase", "phase", rebootv1alpha1.RebootRequestRebooting)
+		return // If we cannot record our intent to reboot - do not reboot
+	}
 
-	if shouldReboot(node) {
-		glog.Info("Reboot requested...")
+	if err := a.markRebootStarted(); err != nil {
+		logger.Error(err, "Failed to record reboot start time, duration metric will be inaccurate")
+	}
 
-		// Set "reboot in progress" and clear reboot needed / reboot
-		node.Annotations[common.RebootInProgressAnnotation] = ""
-		delete(node.Annotations, common.RebootNeededAnnotation)
-		delete(node.Annotations, common.RebootAnnotation)
+	logger.Info("Rebooting node")
+	a.dbusConn.Reboot(false)
+	select {} // Wait for machine to reboot
+}
 
-		// Update the node object
-		_, err := a.client.Core().Nodes().Update(node)
-		if err != nil {
-			glog.Errorf("Failed to set %s annotation: %v", common.RebootInProgressAnnotation, err)
-			return // If we cannot update the state - do not reboot
-		}
+// completeReboot marks a RebootRequest Completed once the agent has come
+// back up after issuing the reboot that put it in the Rebooting phase.
+// This is a naive assumption: the call to reboot is blocking - if we've
+// reached this, assume the node has restarted.
+func (a *rebootAgent) completeReboot(rr *rebootv1alpha1.RebootRequest) {
+	a.logger.Info("Marking reboot complete", "rebootRequest", rr.Name)
 
-		// TODO(aaron): We should drain the node (this is really just for demo purposes - but would be good to demonstrate)
+	a.recordRebootDuration()
 
-		glog.Infof("Rebooting node...")
-		a.dbusConn.Reboot(false)
-		select {} // Wait for machine to reboot
+	if err := a.uncordon(); err != nil {
+		a.logger.Error(err, "Failed to uncordon node after reboot")
 	}
 
-	// Reboot complete - clear the rebootInProgress annotation
-	// This is a niave assumption: the call to reboot is blocking - if we've reached this, assume the node has restarted.
-	if rebootInProgress(node) {
-		glog.Info("Clearing in-progress reboot annotation")
-		delete(node.Annotations, common.RebootInProgressAnnotation)
-		_, err := a.client.Core().Nodes().Update(node)
-		if err != nil {
-			glog.Errorf("Failed to remove %s annotation: %v", common.RebootInProgressAnnotation, err)
-			return
-		}
+	rrCopy := rr.DeepCopy()
+	rrCopy.Status.Phase = rebootv1alpha1.RebootRequestCompleted
+	now := metav1.Now()
+	rrCopy.Status.CompletionTime = &now
+	if _, err := a.rebootClient.RebootV1alpha1().RebootRequests().UpdateStatus(a.ctx, rrCopy, metav1.UpdateOptions{}); err != nil {
+		a.logger.Error(err, "Failed to set RebootRequest phase", "rebootRequest", rr.Name, "phase", rebootv1alpha1.RebootRequestCompleted)
 	}
 }
 
-func shouldReboot(node *v1.Node) bool {
-	_, reboot := node.Annotations[common.RebootAnnotation]
-	_, inProgress := node.Annotations[common.RebootInProgressAnnotation]
+// setPhase updates a RebootRequest's status phase and returns the updated object.
+func (a *rebootAgent) setPhase(rr *rebootv1alpha1.RebootRequest, phase rebootv1alpha1.RebootRequestPhase) (*rebootv1alpha1.RebootRequest, error) {
+	rrCopy := rr.DeepCopy()
+	rrCopy.Status.Phase = phase
+	return a.rebootClient.RebootV1alpha1().RebootRequests().UpdateStatus(a.ctx, rrCopy, metav1.UpdateOptions{})
+}
+
+// markRebootStarted records, on the Node, the time reboot-agent issued the
+// reboot. The agent's own process doesn't survive the reboot, so an
+// in-memory timer can't be used to measure reboot_agent_reboot_duration_seconds
+// - this annotation is read back by recordRebootDuration once the agent
+// comes back up.
+func (a *rebootAgent) markRebootStarted() error {
+	node, err := a.client.CoreV1().Nodes().Get(a.ctx, a.nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
 
-	return reboot && !inProgress
+	nodeCopy := node.DeepCopy()
+	if nodeCopy.Annotations == nil {
+		nodeCopy.Annotations = map[string]string{}
+	}
+	nodeCopy.Annotations[common.RebootStartedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	_, err = a.client.CoreV1().Nodes().Update(a.ctx, nodeCopy, metav1.UpdateOptions{})
+	return err
 }
 
-func rebootInProgress(node *v1.Node) bool {
-	_, inProgress := node.Annotations[common.RebootInProgressAnnotation]
-	return inProgress
+// recordRebootDuration observes reboot_agent_reboot_duration_seconds using
+// the timestamp markRebootStarted persisted on the Node, then clears it.
+func (a *rebootAgent) recordRebootDuration() {
+	node, err := a.client.CoreV1().Nodes().Get(a.ctx, a.nodeName, metav1.GetOptions{})
+	if err != nil {
+		a.logger.Error(err, "Failed to fetch node to record reboot duration")
+		return
+	}
+
+	startedAt, ok := node.Annotations[common.RebootStartedAtAnnotation]
+	if !ok {
+		return
+	}
+	started, err := time.Parse(time.RFC3339, startedAt)
+	if err != nil {
+		a.logger.Error(err, "Failed to parse reboot start timestamp", "value", startedAt)
+		return
+	}
+	metrics.RebootDurationSeconds.Observe(time.Since(started).Seconds())
+
+	nodeCopy := node.DeepCopy()
+	delete(nodeCopy.Annotations, common.RebootStartedAtAnnotation)
+	if _, err := a.client.CoreV1().Nodes().Update(a.ctx, nodeCopy, metav1.UpdateOptions{}); err != nil {
+		a.logger.Error(err, "Failed to clear reboot start timestamp annotation")
+	}
+}
+
+// recordDrainFailure emits a Kubernetes Event against this node so operators
+// can see why a reboot stalled. Events are best-effort: if we can't even
+// fetch the node to attach the event to, just log it.
+func (a *rebootAgent) recordDrainFailure(cause error) {
+	node, err := a.client.CoreV1().Nodes().Get(a.ctx, a.nodeName, metav1.GetOptions{})
+	if err != nil {
+		a.logger.Error(err, "Failed to fetch node to record drain failure event")
+		return
+	}
+	a.recorder.Eventf(node, corev1.EventTypeWarning, "DrainFailed", "Reboot of %s stalled while draining: %v", a.nodeName, cause)
 }
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// groupState is what's persisted per NodeGroup, so the controller's view of
+// in-progress and recent reboots survives a restart without having to replay
+// every RebootRequest to reconstruct it.
+type groupState struct {
+	// Holder is the name of the RebootRequest currently holding this group's
+	// reboot slot, if any.
+	Holder string `json:"holder,omitempty"`
+	// LastRebootTime is when the most recent reboot in this group was approved.
+	LastRebootTime time.Time `json:"lastRebootTime,omitempty"`
+}
+
+// groupStateStore persists per-group reboot state as JSON values in a single
+// ConfigMap, one key per group name.
+type groupStateStore struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+}
+
+func newGroupStateStore(client kubernetes.Interface, namespace, name string) *groupStateStore {
+	return &groupStateStore{client: client, namespace: namespace, name: name}
+}
+
+// load returns the state for every group currently recorded in the
+// ConfigMap. A group with no entry is assumed to have never held the slot.
+func (s *groupStateStore) load(ctx context.Context) (map[string]groupState, error) {
+	cm, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return map[string]groupState{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]groupState, len(cm.Data))
+	for group, data := range cm.Data {
+		var gs groupState
+		if err := json.Unmarshal([]byte(data), &gs); err != nil {
+			return nil, fmt.Errorf("failed to decode state for group %q: %v", group, err)
+		}
+		states[group] = gs
+	}
+	return states, nil
+}
+
+// save records gs as the current state for group, creating the backing
+// ConfigMap if this is the first group to be recorded.
+func (s *groupStateStore) save(ctx context.Context, group string, gs groupState) error {
+	data, err := json.Marshal(gs)
+	if err != nil {
+		return err
+	}
+
+	cm, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: s.name, Namespace: s.namespace},
+			Data:       map[string]string{group: string(data)},
+		}
+		_, err = s.client.CoreV1().ConfigMaps(s.namespace).Create(ctx, cm, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	cmCopy := cm.DeepCopy()
+	if cmCopy.Data == nil {
+		cmCopy.Data = map[string]string{}
+	}
+	cmCopy.Data[group] = string(data)
+	_, err = s.client.CoreV1().ConfigMaps(s.namespace).Update(ctx, cmCopy, metav1.UpdateOptions{})
+	return err
+}
@@ -1,184 +1,496 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
+	"os"
+	"sync"
 	"time"
 
-	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/pkg/api"
-	"k8s.io/client-go/pkg/api/v1"
-	"k8s.io/client-go/pkg/runtime"
-	"k8s.io/client-go/pkg/util/wait"
-	"k8s.io/client-go/pkg/watch"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
 
+	rebootv1alpha1 "github.com/aaronlevy/kube-controller-demo/pkg/apis/reboot/v1alpha1"
+	rebootclientset "github.com/aaronlevy/kube-controller-demo/pkg/client/clientset/versioned"
+	rebootlisters "github.com/aaronlevy/kube-controller-demo/pkg/client/listers/reboot/v1alpha1"
 	"github.com/aaronlevy/kube-controller-demo/common"
+	"github.com/aaronlevy/kube-controller-demo/pkg/controller"
+	"github.com/aaronlevy/kube-controller-demo/pkg/metrics"
 )
 
-// TODO(aaron): make configurable and add MinAvailable
-const maxUnavailable = 1
+// maxSyncRetries is the number of times a RebootRequest key is retried (with
+// backoff) before we give up on it for this resync cycle. It will be
+// re-added the next time the informer observes a change (or on the periodic
+// resync).
+const maxSyncRetries = 5
+
+// leaderElectionResourceName is the name of the ConfigMap used to coordinate
+// which replica of reboot-controller is active.
+const leaderElectionResourceName = "reboot-controller"
+
+// groupStateConfigMapName is the ConfigMap used to persist per-NodeGroup
+// reboot state (see groupStateStore).
+const groupStateConfigMapName = "reboot-controller-state"
 
 func main() {
 	// When running as a pod in-cluster, a kubeconfig is not needed. Instead this will make use of the service account injected into the pod.
 	// However, allow the use of a local kubeconfig as this can make local development & testing easier.
 	kubeconfig := flag.String("kubeconfig", "", "Path to a kubeconfig file")
+	namespace := flag.String("namespace", "kube-system", "Namespace used to hold the leader election lock")
+	maxUnavailable := flag.Int("max-unavailable", 1, "Maximum number of nodes that may be unavailable (rebooting, or NotReady) at once")
+	minAvailable := flag.Int("min-available", 0, "Minimum number of nodes that must remain available (Ready, and not rebooting) before another reboot is approved")
+	workers := flag.Int("workers", 2, "Number of worker goroutines processing the reboot queue")
+	nodeSelectorFlag := flag.String("node-selector", "", "Only consider nodes matching this label selector for reboot (default: all nodes)")
+	rebootWindowStart := flag.String("reboot-window-start", "", `Start of the weekly maintenance window during which reboots are allowed, e.g. "Sat 02:00" (default: no window, reboots allowed at any time)`)
+	rebootWindowLength := flag.String("reboot-window-length", "4h", "Length of the reboot window")
+	rebootWindowTimezone := flag.String("reboot-window-timezone", "UTC", "Timezone the reboot window is specified in")
+	metricsAddr := flag.String("metrics-addr", ":8080", "Address to serve /metrics and /healthz on")
+	leaderElect := flag.Bool("leader-elect", false, "Run with leader election, so multiple replicas can be deployed for HA")
+	leaseDuration := flag.Duration("leader-elect-lease-duration", 15*time.Second, "Duration non-leader candidates wait before forcing acquisition of leadership")
+	renewDeadline := flag.Duration("leader-elect-renew-deadline", 10*time.Second, "Duration the leader retries refreshing leadership before giving it up")
+	retryPeriod := flag.Duration("leader-elect-retry-period", 2*time.Second, "Duration candidates wait between actions to acquire or renew leadership")
 
-	// We log to stderr because glog will default to logging to a file.
-	// By setting this debugging is easier via `kubectl logs`
-	flag.Set("logtostderr", "true")
+	klog.InitFlags(nil)
 	flag.Parse()
 
+	ctx := klog.NewContext(context.Background(), klog.NewKlogr().WithValues("controller", "reboot-controller"))
+	logger := klog.FromContext(ctx)
+
+	go func() {
+		if err := metrics.Serve(*metricsAddr); err != nil {
+			logger.Error(err, "Metrics server exited")
+			os.Exit(1)
+		}
+	}()
+
 	// Build the client config - optionally using a provided kubeconfig file.
 	config, err := common.GetClientConfig(*kubeconfig)
 	if err != nil {
-		glog.Fatalf("Failed to load client config: %v", err)
+		logger.Error(err, "Failed to load client config")
+		os.Exit(1)
 	}
 
 	// Construct the Kubernetes client
 	client, err := kubernetes.NewForConfig(config)
 	if err != nil {
-		glog.Fatalf("Failed to create kubernetes client: %v", err)
+		logger.Error(err, "Failed to create kubernetes client")
+		os.Exit(1)
+	}
+
+	// Construct the client for the reboot.k8s.example.com/v1alpha1 API group
+	rebootClient, err := rebootclientset.NewForConfig(config)
+	if err != nil {
+		logger.Error(err, "Failed to create reboot-request client")
+		os.Exit(1)
+	}
+
+	var nodeSelector labels.Selector
+	if *nodeSelectorFlag != "" {
+		nodeSelector, err = labels.Parse(*nodeSelectorFlag)
+		if err != nil {
+			logger.Error(err, "Invalid --node-selector", "nodeSelector", *nodeSelectorFlag)
+			os.Exit(1)
+		}
+	}
+
+	window, err := parseRebootWindow(*rebootWindowStart, *rebootWindowLength, *rebootWindowTimezone)
+	if err != nil {
+		logger.Error(err, "Invalid reboot window flags")
+		os.Exit(1)
 	}
 
-	glog.Infof("Starting reboot controller")
-	newRebootController(client).controller.Run(wait.NeverStop)
+	groupState := newGroupStateStore(client, *namespace, groupStateConfigMapName)
+
+	mgr := controller.NewManager(client, rebootClient, 10*time.Second)
+	rc := newRebootController(ctx, client, rebootClient, mgr, groupState, window, nodeSelector, *maxUnavailable, *minAvailable, *workers)
+	mgr.Register(rc)
+
+	run := func(stopCh <-chan struct{}) {
+		logger.Info("Starting reboot controller")
+		mgr.Run(ctx, stopCh)
+	}
+
+	if !*leaderElect {
+		run(wait.NeverStop)
+		return
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		logger.Error(err, "Failed to determine hostname for leader election identity")
+		os.Exit(1)
+	}
+
+	controller.RunOrDie(ctx, client, *namespace, leaderElectionResourceName, hostname, controller.LeaderElectionConfig{
+		LeaseDuration: *leaseDuration,
+		RenewDeadline: *renewDeadline,
+		RetryPeriod:   *retryPeriod,
+	}, run)
 }
 
 type rebootController struct {
-	client     kubernetes.Interface
-	nodeLister storeToNodeLister
-	controller cache.ControllerInterface
+	client       kubernetes.Interface
+	rebootClient rebootclientset.Interface
+
+	nodeLister          corelisters.NodeLister
+	rebootRequestLister rebootlisters.RebootRequestLister
+
+	nodeInformer   cache.SharedIndexInformer
+	rebootInformer cache.SharedIndexInformer
+
+	queue workqueue.RateLimitingInterface
+
+	// groupState persists, per NodeGroup, the last reboot time and current
+	// slot holder, so maxUnavailable accounting survives a restart.
+	groupState *groupStateStore
+	// groupLocks serializes approval decisions per NodeGroup, so that two
+	// workers processing RebootRequests for different nodes in the same
+	// group can't both observe maxUnavailable as satisfied and approve
+	// concurrently. Lazily populated: map[string]*sync.Mutex.
+	groupLocks sync.Map
+	// window, if non-nil, restricts approvals to a recurring weekly
+	// maintenance window.
+	window *rebootWindow
+	// nodeSelector, if non-nil, restricts which nodes are eligible for
+	// reboot at all.
+	nodeSelector labels.Selector
+
+	workers        int
+	maxUnavailable int
+	minAvailable   int
+
+	// logger is used by event handlers, which (unlike Start and its
+	// callees) don't carry a context.Context of their own.
+	logger klog.Logger
 }
 
-func newRebootController(client kubernetes.Interface) *rebootController {
+func newRebootController(ctx context.Context, client kubernetes.Interface, rebootClient rebootclientset.Interface, mgr *controller.Manager, groupState *groupStateStore, window *rebootWindow, nodeSelector labels.Selector, maxUnavailable, minAvailable, workers int) *rebootController {
 	rc := &rebootController{
-		client: client,
-	}
-
-	store, controller := cache.NewInformer(
-		&cache.ListWatch{
-			ListFunc: func(alo api.ListOptions) (runtime.Object, error) {
-				var lo v1.ListOptions
-				v1.Convert_api_ListOptions_To_v1_ListOptions(&alo, &lo, nil)
-
-				// We do not add any selectors because we want to watch all nodes.
-				// This is so we can determine the total count of "unavailable" nodes.
-				// However, this could also be implemented using multiple informers (or better, shared-informers)
-				return client.Core().Nodes().List(lo)
-			},
-			WatchFunc: func(alo api.ListOptions) (watch.Interface, error) {
-				var lo v1.ListOptions
-				v1.Convert_api_ListOptions_To_v1_ListOptions(&alo, &lo, nil)
-				return client.Core().Nodes().Watch(lo)
-			},
-		},
-		// The types of objects this informer will return
-		&v1.Node{},
-		// The resync period of this object. This will force a re-queue of all cached objects at this interval.
-		// Every object will trigger the `Updatefunc` even if there have been no actual updates triggered.
-		// In some cases you can set this to a very high interval - as you can assume you will see periodic updates in normal operation.
-		// The interval is set low here for demo purposes.
-		10*time.Second,
-		// Callback Functions to trigger on add/update/delete
-		cache.ResourceEventHandlerFuncs{
-			AddFunc:    rc.handler,
-			UpdateFunc: func(old, new interface{}) { rc.handler(new) },
-			DeleteFunc: rc.handler,
-		},
-	)
+		client:              client,
+		rebootClient:        rebootClient,
+		nodeLister:          mgr.NodeLister,
+		rebootRequestLister: mgr.RebootRequestLister,
+		nodeInformer:        mgr.NodeInformer(),
+		rebootInformer:      mgr.RebootRequestInformer(),
+		queue:               workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "reboot"),
+		groupState:          groupState,
+		window:              window,
+		nodeSelector:        nodeSelector,
+		workers:             workers,
+		maxUnavailable:      maxUnavailable,
+		minAvailable:        minAvailable,
+		logger:              klog.FromContext(ctx),
+	}
+
+	// RebootRequests are what we actually reconcile.
+	rc.rebootInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    rc.enqueue,
+		UpdateFunc: func(old, new interface{}) { rc.enqueue(new) },
+		DeleteFunc: rc.enqueue,
+	})
 
-	rc.controller = controller
-	// Convert the cache.Store to a nodeLister to avoid some boilerplate (e.g. convert runtime.Objects to *v1.Nodes)
-	// TODO(aaron): use upstream cache.StoreToNodeLister once v3.0.0 client-go available
-	rc.nodeLister = storeToNodeLister{store}
+	// Backward-compatibility shim: nodes marked with the legacy
+	// RebootNeededAnnotation (e.g. by tooling that hasn't been updated to
+	// create RebootRequests directly) get one created on their behalf. Once
+	// everything writes RebootRequests directly, this handler - and the
+	// annotation - can be removed.
+	rc.nodeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    rc.shimLegacyAnnotation,
+		UpdateFunc: func(old, new interface{}) { rc.shimLegacyAnnotation(new) },
+	})
 
 	return rc
 }
 
-func (c *rebootController) handler(obj interface{}) {
-	// TODO(aaron): This would be better handled using a workqueue. This will be added to client-go during v1.6.x release.
-	//   As we process objects, add to queue for processing, rather than potentially rebooting whichver node checked in last.
-	//   A good example of this pattern is shown in: https://github.com/kubernetes/community/blob/master/contributors/devel/controllers.md
-	//   We could also protect against operating against a partial cache by not starting processing until cached synced.
+// groupLock returns the mutex guarding approval decisions for group,
+// creating one the first time the group is seen.
+func (c *rebootController) groupLock(group string) *sync.Mutex {
+	mu, _ := c.groupLocks.LoadOrStore(group, &sync.Mutex{})
+	return mu.(*sync.Mutex)
+}
 
-	node := obj.(*v1.Node)
-	glog.V(4).Infof("Received update of node: %s", node.Name)
-	if node.Annotations == nil {
-		return // If node has no annotations, then it doesn't need a reboot
+func (c *rebootController) enqueue(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		c.logger.Error(err, "Failed to get key for object")
+		return
 	}
+	c.queue.Add(key)
+}
 
+// shimLegacyAnnotation creates a RebootRequest for any node still using the
+// old RebootNeededAnnotation, so that the rest of the controller only has to
+// reason about RebootRequests.
+func (c *rebootController) shimLegacyAnnotation(obj interface{}) {
+	node, ok := obj.(*corev1.Node)
+	if !ok || node.Annotations == nil {
+		return
+	}
 	if _, ok := node.Annotations[common.RebootNeededAnnotation]; !ok {
-		return // Node does not need reboot
+		return
 	}
 
-	// Determine if we should reboot based on maximum number of unavailable nodes
-	unavailable, err := c.unavailableNodeCount()
-	if err != nil {
-		glog.Errorf("Failed to determine number of unavailable nodes: %v", err)
+	if _, err := c.rebootRequestLister.Get(node.Name); err == nil {
+		return // Already shimmed
+	} else if !apierrors.IsNotFound(err) {
+		c.logger.Error(err, "Failed to look up RebootRequest for node", "node", node.Name)
 		return
 	}
 
-	if unavailable >= maxUnavailable {
-		glog.Infof("Too many nodes unvailable (%d/%d). Skipping reboot of %s", unavailable, maxUnavailable, node.Name)
-		return
+	c.logger.Info("Creating RebootRequest from legacy annotation", "node", node.Name)
+	_, err := c.rebootClient.RebootV1alpha1().RebootRequests().Create(context.TODO(), &rebootv1alpha1.RebootRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: node.Name},
+		Spec: rebootv1alpha1.RebootRequestSpec{
+			NodeName: node.Name,
+			Reason:   "shimmed from " + common.RebootNeededAnnotation + " annotation",
+		},
+	}, metav1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		c.logger.Error(err, "Failed to create RebootRequest for node", "node", node.Name)
 	}
+}
 
-	// We should not modify the cache object directly, so we make a copy first
-	nodeCopy, err := common.CopyObjToNode(node)
-	if err != nil {
-		glog.Errorf("Failed to make copy of node: %v", err)
-		return
+// clearLegacyAnnotation removes the legacy RebootNeededAnnotation from node
+// once its shimmed RebootRequest has finished, so that a stale annotation
+// can't re-trigger shimLegacyAnnotation into creating a new RebootRequest if
+// the completed one is later garbage collected.
+func (c *rebootController) clearLegacyAnnotation(ctx context.Context, node *corev1.Node) error {
+	if _, ok := node.Annotations[common.RebootNeededAnnotation]; !ok {
+		return nil
 	}
+	nodeCopy := node.DeepCopy()
+	delete(nodeCopy.Annotations, common.RebootNeededAnnotation)
+	_, err := c.client.CoreV1().Nodes().Update(ctx, nodeCopy, metav1.UpdateOptions{})
+	return err
+}
 
-	glog.Infof("Marking node %s for reboot", node.Name)
-	nodeCopy.Annotations[common.RebootAnnotation] = ""
-	if _, err := c.client.Core().Nodes().Update(nodeCopy); err != nil {
-		glog.Errorf("Failed to set %s annotation: %v", common.RebootAnnotation, err)
+// Start processes the reboot queue across c.workers goroutines until stopCh
+// is closed. It assumes the manager has already waited for the shared
+// informer caches to sync before calling Start, so reboot decisions are
+// never made against a partial view of the cluster.
+func (c *rebootController) Start(ctx context.Context, stopCh <-chan struct{}) error {
+	defer c.queue.ShutDown()
+	logger := klog.FromContext(ctx)
+
+	for i := 0; i < c.workers; i++ {
+		go wait.Until(func() { c.runWorker(ctx) }, time.Second, stopCh)
 	}
+
+	logger.Info("Started workers", "count", c.workers)
+	<-stopCh
+	return nil
 }
 
-func (c *rebootController) unavailableNodeCount() (int, error) {
-	nodes, err := c.nodeLister.List()
-	if err != nil {
-		return 0, err
+func (c *rebootController) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
 	}
-	var unavailable int
-	for _, n := range nodes.Items {
-		if nodeIsRebooting(&n) {
-			unavailable++
-			continue
+}
+
+func (c *rebootController) processNextWorkItem(ctx context.Context) bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	logger := klog.FromContext(ctx)
+	if err := c.sync(ctx, key.(string)); err != nil {
+		if c.queue.NumRequeues(key) < maxSyncRetries {
+			logger.Error(err, "Error syncing RebootRequest, will retry", "rebootRequest", key)
+			c.queue.AddRateLimited(key)
+			return true
 		}
-		for _, c := range n.Status.Conditions {
-			if c.Type == v1.NodeReady && c.Status == v1.ConditionFalse {
-				unavailable++
-			}
+		logger.Error(err, "Error syncing RebootRequest, giving up", "rebootRequest", key)
+	}
+
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *rebootController) sync(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx).WithValues("rebootRequest", key)
+
+	rr, err := c.rebootRequestLister.Get(key)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.V(4).Info("RebootRequest no longer exists")
+			return nil
+		}
+		return fmt.Errorf("failed to look up RebootRequest %q: %v", key, err)
+	}
+
+	node, err := c.nodeLister.Get(rr.Spec.NodeName)
+	if err != nil {
+		return fmt.Errorf("failed to look up node %q for RebootRequest %q: %v", rr.Spec.NodeName, key, err)
+	}
+	group := node.Labels[common.NodeGroupLabel]
+	logger = logger.WithValues("node", node.Name, "group", group)
+
+	switch rr.Status.Phase {
+	case rebootv1alpha1.RebootRequestCompleted, rebootv1alpha1.RebootRequestFailed:
+		// The agent finished with this request one way or another - release
+		// the group's reboot slot if this request is still holding it.
+		if err := c.clearLegacyAnnotation(ctx, node); err != nil {
+			logger.Error(err, "Failed to clear legacy reboot-needed annotation")
 		}
+		groupLock := c.groupLock(group)
+		groupLock.Lock()
+		defer groupLock.Unlock()
+		return c.releaseSlot(ctx, group, rr.Name)
+	case "", rebootv1alpha1.RebootRequestPending:
+		// Ours to advance, below.
+	default:
+		// Approved/Draining/Rebooting are owned by the reboot-agent running
+		// on the targeted node.
+		return nil
+	}
+
+	if c.nodeSelector != nil && !c.nodeSelector.Matches(labels.Set(node.Labels)) {
+		logger.V(4).Info("Node does not match --node-selector, ignoring RebootRequest")
+		metrics.RebootsDeniedTotal.WithLabelValues("node-selector").Inc()
+		return nil
+	}
+
+	if c.window != nil && !c.window.Contains(time.Now()) {
+		next := c.window.NextStart(time.Now())
+		logger.V(4).Info("Outside the reboot window, requeuing", "next", next)
+		metrics.RebootsDeniedTotal.WithLabelValues("window-closed").Inc()
+		c.queue.AddAfter(key, time.Until(next))
+		return nil
+	}
+
+	// Hold the group's lock for the remainder of the decision: reading
+	// unavailableNodeCount and approving the request must be atomic with
+	// respect to other workers, or two RebootRequests in the same group
+	// can both observe maxUnavailable as satisfied and both be approved.
+	groupLock := c.groupLock(group)
+	groupLock.Lock()
+	defer groupLock.Unlock()
+
+	unavailable, total, err := c.unavailableNodeCount(group)
+	if err != nil {
+		return fmt.Errorf("failed to determine number of unavailable nodes in group %q: %v", group, err)
+	}
+	metrics.NodesUnavailable.WithLabelValues(group).Set(float64(unavailable))
+
+	minAvailable := c.minAvailable
+	if rr.Spec.MinAvailable != nil {
+		minAvailable = int(*rr.Spec.MinAvailable)
+	}
+
+	if !c.canReboot(unavailable, total, minAvailable) {
+		logger.Info("Too many nodes unavailable, skipping reboot", "unavailable", unavailable, "maxUnavailable", c.maxUnavailable, "minAvailable", minAvailable)
+		metrics.RebootsDeniedTotal.WithLabelValues("max-unavailable").Inc()
+		return nil
 	}
-	return unavailable, nil
+
+	// The persisted slot holder is authoritative across a controller
+	// restart, where the informer cache may not yet reflect a reboot that
+	// was approved just before the restart. Defer to it even though
+	// unavailableNodeCount passed.
+	states, err := c.groupState.load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load group state: %v", err)
+	}
+	if holder := states[group].Holder; holder != "" && holder != rr.Name {
+		logger.V(4).Info("Group's reboot slot is already held, requeuing", "holder", holder)
+		metrics.RebootsDeniedTotal.WithLabelValues("slot-held").Inc()
+		c.queue.AddRateLimited(key)
+		return nil
+	}
+
+	logger.Info("Approving reboot")
+	rrCopy := rr.DeepCopy()
+	rrCopy.Status.Phase = rebootv1alpha1.RebootRequestApproved
+	now := metav1.Now()
+	rrCopy.Status.StartTime = &now
+	if _, err := c.rebootClient.RebootV1alpha1().RebootRequests().UpdateStatus(ctx, rrCopy, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to approve RebootRequest %s: %v", rr.Name, err)
+	}
+	metrics.RebootsApprovedTotal.Inc()
+
+	if err := c.groupState.save(ctx, group, groupState{Holder: rr.Name, LastRebootTime: now.Time}); err != nil {
+		logger.Error(err, "Failed to persist reboot state for group")
+	}
+
+	return nil
 }
 
-func nodeIsRebooting(n *v1.Node) bool {
-	// Check if node is marked for reeboot-in-progress
-	if n.Annotations == nil {
-		return false // No annotations - not marked as needing reboot
+// releaseSlot clears the persisted reboot slot for group if it's currently
+// recorded as held by name, preserving the group's last reboot time.
+func (c *rebootController) releaseSlot(ctx context.Context, group, name string) error {
+	states, err := c.groupState.load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load group state: %v", err)
 	}
-	if _, ok := n.Annotations[common.RebootInProgressAnnotation]; ok {
-		return true
+	if states[group].Holder != name {
+		return nil
 	}
-	// Check if node is already marked for immediate reboot
-	_, ok := n.Annotations[common.RebootAnnotation]
-	return ok
+	return c.groupState.save(ctx, group, groupState{LastRebootTime: states[group].LastRebootTime})
 }
 
-// The current client-go StoreToNodeLister expects api.Node - but client returns v1.Node. Add this shim until next release
-type storeToNodeLister struct {
-	cache.Store
+// canReboot reports whether another node may be marked for reboot, given the
+// number of nodes already unavailable and the total number of nodes, both
+// counted within the node's NodeGroup. minAvailable is the caller's resolved value - the cluster-wide
+// --min-available flag, unless the RebootRequest overrides it. It accounts
+// for maxUnavailable and minAvailable together so that, for small clusters,
+// minAvailable can be the tighter constraint.
+func (c *rebootController) canReboot(unavailable, total, minAvailable int) bool {
+	if unavailable >= c.maxUnavailable {
+		return false
+	}
+	if total-(unavailable+1) < minAvailable {
+		return false
+	}
+	return true
 }
 
-func (s *storeToNodeLister) List() (machines v1.NodeList, err error) {
-	for _, m := range s.Store.List() {
-		machines.Items = append(machines.Items, *(m.(*v1.Node)))
+// unavailableNodeCount counts nodes in group that are NotReady, plus nodes
+// in group with a RebootRequest that is past Pending (i.e. already approved,
+// draining, or rebooting). maxUnavailable and minAvailable are enforced per
+// group, so a busy group of workers can't block reboots of masters, or vice
+// versa.
+func (c *rebootController) unavailableNodeCount(group string) (unavailable, total int, err error) {
+	nodes, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		return 0, 0, err
+	}
+
+	rrs, err := c.rebootRequestLister.List(labels.Everything())
+	if err != nil {
+		return 0, 0, err
+	}
+	activeReboots := make(map[string]bool, len(rrs))
+	for _, rr := range rrs {
+		switch rr.Status.Phase {
+		case rebootv1alpha1.RebootRequestApproved, rebootv1alpha1.RebootRequestDraining, rebootv1alpha1.RebootRequestRebooting:
+			activeReboots[rr.Spec.NodeName] = true
+		}
+	}
+
+	for _, n := range nodes {
+		if n.Labels[common.NodeGroupLabel] != group {
+			continue
+		}
+		total++
+		if activeReboots[n.Name] {
+			unavailable++
+			continue
+		}
+		for _, cond := range n.Status.Conditions {
+			if cond.Type == corev1.NodeReady && cond.Status == corev1.ConditionFalse {
+				unavailable++
+			}
+		}
 	}
-	return machines, nil
+	return unavailable, total, nil
 }
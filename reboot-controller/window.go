@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// rebootWindow is a recurring weekly maintenance window, e.g. "Sat 02:00" for
+// 4h, modeled after locksmith's coordinated reboot windows. A nil
+// *rebootWindow means reboots are allowed at any time.
+type rebootWindow struct {
+	weekday  time.Weekday
+	hour     int
+	minute   int
+	length   time.Duration
+	location *time.Location
+}
+
+// parseRebootWindow builds a rebootWindow from the --reboot-window-* flags.
+// An empty start disables the window entirely (nil, nil is returned).
+func parseRebootWindow(start, length, timezone string) (*rebootWindow, error) {
+	if start == "" {
+		return nil, nil
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --reboot-window-timezone %q: %v", timezone, err)
+	}
+
+	fields := strings.Fields(start)
+	if len(fields) != 2 {
+		return nil, fmt.Errorf(`invalid --reboot-window-start %q: expected "Mon 15:04"`, start)
+	}
+	weekday, err := parseWeekday(fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid --reboot-window-start %q: %v", start, err)
+	}
+	t, err := time.Parse("15:04", fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid --reboot-window-start %q: %v", start, err)
+	}
+
+	d, err := time.ParseDuration(length)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --reboot-window-length %q: %v", length, err)
+	}
+
+	return &rebootWindow{
+		weekday:  weekday,
+		hour:     t.Hour(),
+		minute:   t.Minute(),
+		length:   d,
+		location: loc,
+	}, nil
+}
+
+var weekdaysByPrefix = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func parseWeekday(s string) (time.Weekday, error) {
+	if len(s) < 3 {
+		return 0, fmt.Errorf("unrecognized weekday %q", s)
+	}
+	lower := strings.ToLower(s)
+	// Accept only the three-letter abbreviation or the full weekday name.
+	if d, ok := weekdaysByPrefix[lower[:3]]; ok && (len(lower) == 3 || lower == strings.ToLower(d.String())) {
+		return d, nil
+	}
+	return 0, fmt.Errorf("unrecognized weekday %q", s)
+}
+
+// mostRecentStart returns the latest window start at or before t.
+func (w *rebootWindow) mostRecentStart(t time.Time) time.Time {
+	t = t.In(w.location)
+	candidate := time.Date(t.Year(), t.Month(), t.Day(), w.hour, w.minute, 0, 0, w.location)
+	for candidate.Weekday() != w.weekday || candidate.After(t) {
+		candidate = candidate.AddDate(0, 0, -1)
+	}
+	return candidate
+}
+
+// Contains reports whether t falls within the window.
+func (w *rebootWindow) Contains(t time.Time) bool {
+	start := w.mostRecentStart(t)
+	return !t.Before(start) && t.Before(start.Add(w.length))
+}
+
+// NextStart returns the window's next start time strictly after t, so a
+// RebootRequest that arrives outside the window can be requeued to be
+// reconsidered once it opens.
+func (w *rebootWindow) NextStart(t time.Time) time.Time {
+	t = t.In(w.location)
+	candidate := time.Date(t.Year(), t.Month(), t.Day(), w.hour, w.minute, 0, 0, w.location)
+	for candidate.Weekday() != w.weekday || !candidate.After(t) {
+		candidate = candidate.AddDate(0, 0, 1)
+	}
+	return candidate
+}
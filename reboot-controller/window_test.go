@@ -0,0 +1,175 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load location %q: %v", name, err)
+	}
+	return loc
+}
+
+func TestParseRebootWindow(t *testing.T) {
+	cases := []struct {
+		name    string
+		start   string
+		length  string
+		tz      string
+		wantNil bool
+		wantErr bool
+	}{
+		{name: "empty start disables window", start: "", length: "4h", tz: "UTC", wantNil: true},
+		{name: "valid window", start: "Sat 02:00", length: "4h", tz: "UTC"},
+		{name: "abbreviated weekday is case-insensitive", start: "sAt 02:00", length: "4h", tz: "UTC"},
+		{name: "invalid timezone", start: "Sat 02:00", length: "4h", tz: "Not/ARealZone", wantErr: true},
+		{name: "missing time field", start: "Sat", length: "4h", tz: "UTC", wantErr: true},
+		{name: "unrecognized weekday", start: "Satur 02:00", length: "4h", tz: "UTC", wantErr: true},
+		{name: "malformed time of day", start: "Sat 2:00am", length: "4h", tz: "UTC", wantErr: true},
+		{name: "invalid length", start: "Sat 02:00", length: "not-a-duration", tz: "UTC", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, err := parseRebootWindow(tc.start, tc.length, tc.tz)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("parseRebootWindow(%q, %q, %q) = nil error, want error", tc.start, tc.length, tc.tz)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRebootWindow(%q, %q, %q) = %v, want no error", tc.start, tc.length, tc.tz, err)
+			}
+			if tc.wantNil && w != nil {
+				t.Fatalf("parseRebootWindow(%q, %q, %q) = %+v, want nil", tc.start, tc.length, tc.tz, w)
+			}
+			if !tc.wantNil && w == nil {
+				t.Fatalf("parseRebootWindow(%q, %q, %q) = nil, want a window", tc.start, tc.length, tc.tz)
+			}
+		})
+	}
+}
+
+func TestRebootWindowContains(t *testing.T) {
+	utc := time.UTC
+	// Saturday 02:00 UTC for 4h, i.e. [Sat 02:00, Sat 06:00).
+	w := &rebootWindow{weekday: time.Saturday, hour: 2, minute: 0, length: 4 * time.Hour, location: utc}
+
+	cases := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{name: "before window on the same day", t: time.Date(2026, 7, 25, 1, 59, 59, 0, utc), want: false},
+		{name: "exactly at start", t: time.Date(2026, 7, 25, 2, 0, 0, 0, utc), want: true},
+		{name: "midway through", t: time.Date(2026, 7, 25, 4, 0, 0, 0, utc), want: true},
+		{name: "one second before end", t: time.Date(2026, 7, 25, 5, 59, 59, 0, utc), want: true},
+		{name: "exactly at end is excluded", t: time.Date(2026, 7, 25, 6, 0, 0, 0, utc), want: false},
+		{name: "wrong day entirely", t: time.Date(2026, 7, 22, 4, 0, 0, 0, utc), want: false},
+		{name: "following week, same point in window", t: time.Date(2026, 8, 1, 3, 0, 0, 0, utc), want: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := w.Contains(tc.t); got != tc.want {
+				t.Errorf("Contains(%v) = %v, want %v", tc.t, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRebootWindowNextStart(t *testing.T) {
+	utc := time.UTC
+	w := &rebootWindow{weekday: time.Saturday, hour: 2, minute: 0, length: 4 * time.Hour, location: utc}
+
+	cases := []struct {
+		name string
+		t    time.Time
+		want time.Time
+	}{
+		{
+			name: "earlier in the same week",
+			t:    time.Date(2026, 7, 20, 0, 0, 0, 0, utc), // Monday
+			want: time.Date(2026, 7, 25, 2, 0, 0, 0, utc),
+		},
+		{
+			name: "inside the current window still returns the following week",
+			t:    time.Date(2026, 7, 25, 3, 0, 0, 0, utc),
+			want: time.Date(2026, 8, 1, 2, 0, 0, 0, utc),
+		},
+		{
+			name: "just before the window wraps to later the same day",
+			t:    time.Date(2026, 7, 25, 1, 59, 0, 0, utc),
+			want: time.Date(2026, 7, 25, 2, 0, 0, 0, utc),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := w.NextStart(tc.t); !got.Equal(tc.want) {
+				t.Errorf("NextStart(%v) = %v, want %v", tc.t, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestRebootWindowContainsAcrossDST exercises mostRecentStart/Contains across
+// a spring-forward DST transition, where the naive local wall-clock
+// arithmetic in time.Date could otherwise land on a skipped or repeated hour.
+func TestRebootWindowContainsAcrossDST(t *testing.T) {
+	ny := mustLoadLocation(t, "America/New_York")
+	// 2026-03-08 is when US clocks spring forward at 02:00 -> 03:00 local.
+	w := &rebootWindow{weekday: time.Sunday, hour: 1, minute: 30, length: 2 * time.Hour, location: ny}
+
+	cases := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{name: "before the window starts", t: time.Date(2026, 3, 8, 1, 0, 0, 0, ny), want: false},
+		{name: "at the window start, before the DST jump", t: time.Date(2026, 3, 8, 1, 30, 0, 0, ny), want: true},
+		{name: "after the jump, still within window length", t: time.Date(2026, 3, 8, 4, 0, 0, 0, ny), want: true},
+		{name: "a week later at the same wall-clock time", t: time.Date(2026, 3, 15, 2, 0, 0, 0, ny), want: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := w.Contains(tc.t); got != tc.want {
+				t.Errorf("Contains(%v) = %v, want %v", tc.t, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRebootControllerCanReboot(t *testing.T) {
+	cases := []struct {
+		name           string
+		maxUnavailable int
+		minAvailable   int
+		unavailable    int
+		total          int
+		want           bool
+	}{
+		{name: "below maxUnavailable", maxUnavailable: 2, minAvailable: 0, unavailable: 0, total: 10, want: true},
+		{name: "at maxUnavailable", maxUnavailable: 2, minAvailable: 0, unavailable: 2, total: 10, want: false},
+		{name: "minAvailable is the tighter constraint on a small cluster", maxUnavailable: 3, minAvailable: 3, unavailable: 0, total: 3, want: false},
+		{name: "minAvailable satisfied exactly", maxUnavailable: 3, minAvailable: 2, unavailable: 0, total: 3, want: true},
+		{name: "both constraints satisfied", maxUnavailable: 1, minAvailable: 1, unavailable: 0, total: 3, want: true},
+		{name: "maxUnavailable zero never allows a reboot", maxUnavailable: 0, minAvailable: 0, unavailable: 0, total: 10, want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &rebootController{maxUnavailable: tc.maxUnavailable}
+			if got := c.canReboot(tc.unavailable, tc.total, tc.minAvailable); got != tc.want {
+				t.Errorf("canReboot(%d, %d, %d) with maxUnavailable=%d = %v, want %v",
+					tc.unavailable, tc.total, tc.minAvailable, tc.maxUnavailable, got, tc.want)
+			}
+		})
+	}
+}